@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// restDirectiveSDL defines the built-in @rest directive so users don't have
+// to declare it themselves, analogous to google.api.http annotations:
+//
+//	query GetUser($id: ID! @rest(in: "path")) @rest(path: "/users/{id}", method: "GET") {
+//	  user(id: $id) { name }
+//	}
+const restDirectiveSDL = "\ndirective @rest(path: String, method: String, in: String) on QUERY | MUTATION | VARIABLE_DEFINITION\n"
+
+// withBuiltinDirectives appends the built-in directive definitions to a
+// schema's SDL before it's parsed, so @rest can be used on operations and
+// variables without the user declaring it in their own schema file.
+func withBuiltinDirectives(sdl string) string {
+	return sdl + restDirectiveSDL
+}
+
+// operationRestOverride reads the operation-level @rest(path, method)
+// directive, if present.
+func operationRestOverride(op *ast.OperationDefinition) (path, method string, ok bool) {
+	d := op.Directives.ForName("rest")
+	if d == nil {
+		return "", "", false
+	}
+	if a := d.Arguments.ForName("path"); a != nil {
+		path = a.Value.Raw
+	}
+	if a := d.Arguments.ForName("method"); a != nil {
+		method = strings.ToUpper(a.Value.Raw)
+	}
+	return path, method, true
+}
+
+// variableRestIn reads a variable's @rest(in: "...") directive, if present.
+func variableRestIn(v *ast.VariableDefinition) (string, bool) {
+	d := v.Directives.ForName("rest")
+	if d == nil {
+		return "", false
+	}
+	if a := d.Arguments.ForName("in"); a != nil {
+		return a.Value.Raw, true
+	}
+	return "", false
+}
+
+// buildDirectiveRESTOperation maps an operation's variables to OpenAPI
+// parameters/request body per their @rest(in: ...) directives (falling back
+// to the same path/input-object heuristic buildRESTSpec uses when a
+// variable has no explicit directive).
+func buildDirectiveRESTOperation(schema *ast.Schema, queryOp *ast.OperationDefinition, summary, restPath string, responses *openapi3.Responses) *openapi3.Operation {
+	pathParams := pathParamNames(restPath)
+	op := &openapi3.Operation{
+		Summary:     summary,
+		Description: "REST mapping derived from @rest directives on the operation and its variables.",
+		Responses:   responses,
+	}
+
+	var bodyProps map[string]*openapi3.SchemaRef
+	var bodyRequired []string
+
+	for _, v := range queryOp.VariableDefinitions {
+		name := v.Variable
+		schemaRef := graphqlTypeToJSONSchema(schema, v.Type)
+
+		in, explicit := variableRestIn(v)
+		if !explicit {
+			switch {
+			case isInputObjectType(schema, v.Type):
+				in = "body"
+			case pathParams[name]:
+				in = "path"
+			default:
+				in = "query"
+			}
+		}
+
+		if in == "body" {
+			if bodyProps == nil {
+				bodyProps = map[string]*openapi3.SchemaRef{}
+			}
+			bodyProps[name] = schemaRef
+			if v.Type.NonNull {
+				bodyRequired = append(bodyRequired, name)
+			}
+			continue
+		}
+
+		param := &openapi3.Parameter{
+			Name:     name,
+			In:       in,
+			Required: in == "path" || v.Type.NonNull,
+			Schema:   schemaRef,
+		}
+		op.Parameters = append(op.Parameters, &openapi3.ParameterRef{Value: param})
+	}
+
+	if len(bodyProps) > 0 {
+		bodySchema := openapi3.NewObjectSchema()
+		bodySchema.Properties = bodyProps
+		if len(bodyRequired) > 0 {
+			bodySchema.Required = bodyRequired
+		}
+		op.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{
+			Required: true,
+			Content:  openapi3.NewContentWithJSONSchema(bodySchema),
+		}}
+	}
+
+	return op
+}
+
+func isInputObjectType(schema *ast.Schema, t *ast.Type) bool {
+	def := schema.Types[t.Name()]
+	return def != nil && def.Kind == ast.InputObject
+}
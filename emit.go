@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Emitter produces one output artifact (OpenAPI, Postman, HAR, HTTPie, ...)
+// for a generated operation. --emit selects which of these run.
+type Emitter interface {
+	// Name is the --emit token that selects this emitter, e.g. "postman".
+	Name() string
+	// FileSuffix is appended to the --out basename to build this emitter's
+	// own output filename, e.g. ".postman_collection.json".
+	FileSuffix() string
+	// Emit renders the artifact as bytes.
+	Emit(spec *openapi3.T, format, endpoint, query string, variables map[string]any) ([]byte, error)
+}
+
+var emitters = map[string]Emitter{
+	"openapi": openAPIEmitter{},
+	"postman": postmanEmitter{},
+	"har":     harEmitter{},
+	"httpie":  httpieEmitter{},
+}
+
+// parseEmitList turns "--emit openapi,postman" into a slice of Emitters,
+// defaulting to just OpenAPI when emit is empty.
+func parseEmitList(raw string) ([]Emitter, error) {
+	if strings.TrimSpace(raw) == "" {
+		return []Emitter{emitters["openapi"]}, nil
+	}
+	var out []Emitter
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		e, ok := emitters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown --emit target %q (want one of openapi, postman, har, httpie)", name)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// emitAll runs every selected emitter and writes its output next to outPath,
+// inferring each emitter's filename from outPath's basename. The primary
+// "openapi" emitter keeps writing directly to outPath (or stdout) as before;
+// the rest always need a base path to derive a filename from.
+func emitAll(list []Emitter, spec *openapi3.T, outPath, format, endpoint, query string, variables map[string]any) error {
+	base := outPath
+	if base == "" {
+		base = "openapi"
+	}
+	base = strings.TrimSuffix(base, path.Ext(base))
+
+	for _, e := range list {
+		if e.Name() == "openapi" {
+			// writeSpec already renders spec to bytes and writes it (or stdout);
+			// calling Emit here too would just re-serialize the same spec.
+			if err := writeSpec(spec, outPath, format); err != nil {
+				return fmt.Errorf("openapi: %w", err)
+			}
+			continue
+		}
+		b, err := e.Emit(spec, format, endpoint, query, variables)
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		filePath := base + e.FileSuffix()
+		if err := writeFile(filePath, b); err != nil {
+			return fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		fmt.Printf("wrote %s\n", filePath)
+	}
+	return nil
+}
+
+type openAPIEmitter struct{}
+
+func (openAPIEmitter) Name() string       { return "openapi" }
+func (openAPIEmitter) FileSuffix() string { return "" }
+func (openAPIEmitter) Emit(spec *openapi3.T, format, endpoint, query string, variables map[string]any) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		return spec.MarshalJSON()
+	default:
+		return specToYAML(spec)
+	}
+}
+
+// queryOperation is one operation extracted from a (possibly multi-operation)
+// query document, along with its own source text and the names of the
+// variables it declares.
+type queryOperation struct {
+	name          string
+	query         string
+	variableNames []string
+}
+
+// variables returns the subset of all that this operation actually declares,
+// so each operation's example only carries its own variables.
+func (o queryOperation) variables(all map[string]any) map[string]any {
+	out := map[string]any{}
+	for _, n := range o.variableNames {
+		if v, ok := all[n]; ok {
+			out[n] = v
+		}
+	}
+	return out
+}
+
+// splitQueryOperations reparses query with parser.ParseQuery (schema-free,
+// like the rest of this tool) and returns one queryOperation per operation in
+// the document, falling back to a single operation covering the whole
+// document under fallbackName if it doesn't parse on its own.
+func splitQueryOperations(query, fallbackName string) []queryOperation {
+	doc, err := parser.ParseQuery(&ast.Source{Name: "query", Input: query})
+	if err != nil || len(doc.Operations) == 0 {
+		return []queryOperation{{name: fallbackName, query: query}}
+	}
+
+	ops := make([]queryOperation, 0, len(doc.Operations))
+	for _, op := range doc.Operations {
+		name := op.Name
+		if name == "" {
+			name = string(op.Operation)
+		}
+		opQuery := query
+		if op.Position != nil {
+			opQuery = strings.TrimSpace(query[op.Position.Start:op.Position.End])
+		}
+		names := make([]string, 0, len(op.VariableDefinitions))
+		for _, v := range op.VariableDefinitions {
+			names = append(names, v.Variable)
+		}
+		ops = append(ops, queryOperation{name: name, query: opQuery, variableNames: names})
+	}
+	return ops
+}
+
+type postmanEmitter struct{}
+
+func (postmanEmitter) Name() string       { return "postman" }
+func (postmanEmitter) FileSuffix() string { return ".postman_collection.json" }
+
+// Emit renders one Postman "folder" (item group) per operation in query, each
+// holding a single request pre-populated with the subset of variables that
+// operation declares.
+func (postmanEmitter) Emit(spec *openapi3.T, format, endpoint, query string, variables map[string]any) ([]byte, error) {
+	name := spec.Info.Title
+	collection := map[string]any{
+		"info": map[string]any{
+			"name":   name,
+			"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		"item":     postmanFolders(name, endpoint, query, variables),
+		"variable": postmanVariables(variables),
+	}
+	return json.MarshalIndent(collection, "", "  ")
+}
+
+func postmanFolders(collectionName, endpoint, query string, variables map[string]any) []map[string]any {
+	ops := splitQueryOperations(query, collectionName)
+	folders := make([]map[string]any, 0, len(ops))
+	for _, op := range ops {
+		opVars := op.variables(variables)
+		folders = append(folders, map[string]any{
+			"name":     op.name,
+			"item":     []map[string]any{postmanRequestItem(op.name, endpoint, op.query, opVars)},
+			"variable": postmanVariables(opVars),
+		})
+	}
+	return folders
+}
+
+func postmanRequestItem(name, endpoint, query string, variables map[string]any) map[string]any {
+	body, _ := json.MarshalIndent(map[string]any{"query": query, "variables": variables}, "", "  ")
+	return map[string]any{
+		"name": name,
+		"request": map[string]any{
+			"method": "POST",
+			"header": []map[string]any{
+				{"key": "Content-Type", "value": "application/json"},
+			},
+			"body": map[string]any{
+				"mode": "raw",
+				"raw":  string(body),
+			},
+			"url": map[string]any{"raw": endpoint},
+		},
+	}
+}
+
+func postmanVariables(variables map[string]any) []map[string]any {
+	vars := make([]map[string]any, 0, len(variables))
+	for k, v := range variables {
+		vars = append(vars, map[string]any{"key": k, "value": fmt.Sprintf("%v", v)})
+	}
+	return vars
+}
+
+type harEmitter struct{}
+
+func (harEmitter) Name() string       { return "har" }
+func (harEmitter) FileSuffix() string { return ".har" }
+
+// Emit renders one log.entries[] item per operation in query, so the output
+// drops straight into browser devtools or replay tools like vegeta/k6.
+func (harEmitter) Emit(spec *openapi3.T, format, endpoint, query string, variables map[string]any) ([]byte, error) {
+	ops := splitQueryOperations(query, "operation")
+	entries := make([]map[string]any, 0, len(ops))
+	for _, op := range ops {
+		body, _ := json.Marshal(map[string]any{"query": op.query, "variables": op.variables(variables)})
+		entries = append(entries, map[string]any{
+			"request": map[string]any{
+				"method":      "POST",
+				"url":         endpoint,
+				"httpVersion": "HTTP/1.1",
+				"headers": []map[string]any{
+					{"name": "Content-Type", "value": "application/json"},
+				},
+				"queryString": []map[string]any{},
+				"postData": map[string]any{
+					"mimeType": "application/json",
+					"text":     string(body),
+				},
+				"headersSize": -1,
+				"bodySize":    len(body),
+			},
+			"response": map[string]any{
+				"status":      200,
+				"statusText":  "OK",
+				"httpVersion": "HTTP/1.1",
+				"headers":     []map[string]any{},
+				"content":     map[string]any{"size": 0, "mimeType": "application/json"},
+				"redirectURL": "",
+				"headersSize": -1,
+				"bodySize":    -1,
+			},
+			"cache":           map[string]any{},
+			"timings":         map[string]any{"send": 0, "wait": 0, "receive": 0},
+			"startedDateTime": "1970-01-01T00:00:00.000Z",
+		})
+	}
+
+	har := map[string]any{
+		"log": map[string]any{
+			"version": "1.2",
+			"creator": map[string]any{"name": "all-the-curls", "version": spec.Info.Version},
+			"entries": entries,
+		},
+	}
+	return json.MarshalIndent(har, "", "  ")
+}
+
+type httpieEmitter struct{}
+
+func (httpieEmitter) Name() string       { return "httpie" }
+func (httpieEmitter) FileSuffix() string { return ".http" }
+func (httpieEmitter) Emit(spec *openapi3.T, format, endpoint, query string, variables map[string]any) ([]byte, error) {
+	body, _ := json.Marshal(map[string]any{"query": query, "variables": variables})
+	cmd := fmt.Sprintf("http --raw %s POST %s Content-Type:application/json\n", shellEscape(string(body)), shellEscape(endpoint))
+	return []byte(cmd), nil
+}
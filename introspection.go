@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	gqlparser "github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// introspectionQuery is the standard GraphQL introspection query used by
+// virtually every GraphQL tool to fetch a schema's SDL-equivalent shape.
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+  }
+}
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+type introspectionInputValue struct {
+	Name         string               `json:"name"`
+	Description  string               `json:"description"`
+	Type         introspectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+type introspectionField struct {
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Args        []introspectionInputValue `json:"args"`
+	Type        introspectionTypeRef      `json:"type"`
+}
+
+type introspectionEnumValue struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type introspectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	Fields        []introspectionField      `json:"fields"`
+	InputFields   []introspectionInputValue `json:"inputFields"`
+	Interfaces    []introspectionTypeRef    `json:"interfaces"`
+	EnumValues    []introspectionEnumValue  `json:"enumValues"`
+	PossibleTypes []introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionSchema struct {
+	QueryType        *introspectionTypeRef `json:"queryType"`
+	MutationType     *introspectionTypeRef `json:"mutationType"`
+	SubscriptionType *introspectionTypeRef `json:"subscriptionType"`
+	Types            []introspectionType   `json:"types"`
+}
+
+type introspectionResponse struct {
+	Data struct {
+		Schema introspectionSchema `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// loadSchemaFromIntrospection POSTs the standard introspection query to
+// endpoint, reconstructs an SDL document from the result, and parses it the
+// same way main parses a local --schema file (gqlparser.LoadSchema with the
+// built-in directives appended). Used when the caller passes
+// --schema-from-endpoint instead of --schema.
+func loadSchemaFromIntrospection(endpoint string, headers http.Header, insecureSkipVerify bool) (*ast.Schema, error) {
+	body, _ := json.Marshal(map[string]any{"query": introspectionQuery})
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, vs := range headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify},
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("introspection request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection request returned status %d", resp.StatusCode)
+	}
+
+	var parsed introspectionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("decoding introspection response: %w", err)
+	}
+	if len(parsed.Errors) > 0 {
+		return nil, fmt.Errorf("introspection disabled or failed: %s (is introspection enabled on this endpoint?)", parsed.Errors[0].Message)
+	}
+	if parsed.Data.Schema.QueryType == nil {
+		return nil, fmt.Errorf("introspection response had no __schema.queryType; is introspection enabled on this endpoint?")
+	}
+
+	sdl := renderSDL(parsed.Data.Schema)
+	return gqlparser.LoadSchema(&ast.Source{Name: "introspection", Input: withBuiltinDirectives(sdl)})
+}
+
+// renderSDL turns an introspection result back into GraphQL SDL text so it
+// can be fed through the same gqlparser.LoadSchema path as a local file.
+func renderSDL(s introspectionSchema) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "schema {\n")
+	if s.QueryType != nil {
+		fmt.Fprintf(&b, "  query: %s\n", s.QueryType.Name)
+	}
+	if s.MutationType != nil {
+		fmt.Fprintf(&b, "  mutation: %s\n", s.MutationType.Name)
+	}
+	if s.SubscriptionType != nil {
+		fmt.Fprintf(&b, "  subscription: %s\n", s.SubscriptionType.Name)
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	types := make([]introspectionType, len(s.Types))
+	copy(types, s.Types)
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+
+	for _, t := range types {
+		if strings.HasPrefix(t.Name, "__") {
+			continue // introspection meta-types are built in to gqlparser already
+		}
+		switch t.Kind {
+		case "SCALAR":
+			if isBuiltinScalar(t.Name) {
+				continue
+			}
+			fmt.Fprintf(&b, "scalar %s\n\n", t.Name)
+		case "OBJECT":
+			renderFieldedType(&b, "type", t)
+		case "INTERFACE":
+			renderFieldedType(&b, "interface", t)
+		case "INPUT_OBJECT":
+			fmt.Fprintf(&b, "input %s {\n", t.Name)
+			for _, f := range t.InputFields {
+				fmt.Fprintf(&b, "  %s: %s\n", f.Name, renderTypeRef(f.Type))
+			}
+			fmt.Fprintf(&b, "}\n\n")
+		case "ENUM":
+			fmt.Fprintf(&b, "enum %s {\n", t.Name)
+			for _, v := range t.EnumValues {
+				fmt.Fprintf(&b, "  %s\n", v.Name)
+			}
+			fmt.Fprintf(&b, "}\n\n")
+		case "UNION":
+			names := make([]string, 0, len(t.PossibleTypes))
+			for _, p := range t.PossibleTypes {
+				names = append(names, p.Name)
+			}
+			fmt.Fprintf(&b, "union %s = %s\n\n", t.Name, strings.Join(names, " | "))
+		}
+	}
+
+	return b.String()
+}
+
+func renderFieldedType(b *strings.Builder, keyword string, t introspectionType) {
+	fmt.Fprintf(b, "%s %s", keyword, t.Name)
+	if len(t.Interfaces) > 0 {
+		names := make([]string, 0, len(t.Interfaces))
+		for _, i := range t.Interfaces {
+			names = append(names, i.Name)
+		}
+		fmt.Fprintf(b, " implements %s", strings.Join(names, " & "))
+	}
+	fmt.Fprintf(b, " {\n")
+	for _, f := range t.Fields {
+		args := ""
+		if len(f.Args) > 0 {
+			parts := make([]string, 0, len(f.Args))
+			for _, a := range f.Args {
+				parts = append(parts, fmt.Sprintf("%s: %s", a.Name, renderTypeRef(a.Type)))
+			}
+			args = "(" + strings.Join(parts, ", ") + ")"
+		}
+		fmt.Fprintf(b, "  %s%s: %s\n", f.Name, args, renderTypeRef(f.Type))
+	}
+	fmt.Fprintf(b, "}\n\n")
+}
+
+func renderTypeRef(t introspectionTypeRef) string {
+	switch t.Kind {
+	case "NON_NULL":
+		return renderTypeRef(*t.OfType) + "!"
+	case "LIST":
+		return "[" + renderTypeRef(*t.OfType) + "]"
+	default:
+		return t.Name
+	}
+}
+
+func isBuiltinScalar(name string) bool {
+	switch name {
+	case "Int", "Float", "String", "Boolean", "ID":
+		return true
+	}
+	return false
+}
+
+// headerList implements flag.Value so --header can be passed repeatedly,
+// e.g. --header 'Authorization: Bearer xyz'.
+type headerList struct {
+	headers http.Header
+}
+
+func newHeaderList() *headerList {
+	return &headerList{headers: http.Header{}}
+}
+
+func (h *headerList) String() string {
+	if h == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", h.headers)
+}
+
+func (h *headerList) Set(v string) error {
+	parts := strings.SplitN(v, ":", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("expected 'Key: Value', got %q", v)
+	}
+	h.headers.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	return nil
+}
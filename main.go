@@ -28,6 +28,11 @@ import (
 //   --title "My GraphQL as REST" --version 1.0.0
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		cmdValidate(os.Args[2:])
+		return
+	}
+
 	var schemaPath string
 	var queryPath string
 	var endpoint string
@@ -38,6 +43,13 @@ func main() {
 	var title string
 	var version string
 	var interactive bool
+	var mode string
+	var pathTemplate string
+	var schemaFromEndpoint string
+	var insecureSkipVerify bool
+	var emit string
+	var transport string
+	headers := newHeaderList()
 
 	flag.StringVar(&schemaPath, "schema", "", "Path to GraphQL schema SDL (.graphql/.gql)")
 	flag.StringVar(&queryPath, "query", "", "Path to GraphQL query document (.graphql/.gql)")
@@ -49,9 +61,16 @@ func main() {
 	flag.StringVar(&title, "title", "GraphQL as REST", "OpenAPI document title")
 	flag.StringVar(&version, "version", "1.0.0", "OpenAPI document version")
 	flag.BoolVar(&interactive, "interactive", false, "Prompt for missing inputs interactively")
+	flag.StringVar(&mode, "mode", "graphql", "Output mode: graphql (single /graphql passthrough) or rest (one path per top-level field)")
+	flag.StringVar(&pathTemplate, "path-template", "", "Comma-separated field=/path/{arg} overrides for --mode rest (e.g. user=/users/{id})")
+	flag.StringVar(&schemaFromEndpoint, "schema-from-endpoint", "", "Fetch the schema via introspection from this URL instead of --schema")
+	flag.BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Skip TLS certificate verification when using --schema-from-endpoint")
+	flag.Var(headers, "header", "Extra header to send with --schema-from-endpoint requests, e.g. --header 'Authorization: Bearer xyz' (repeatable)")
+	flag.StringVar(&emit, "emit", "openapi", "Comma-separated artifacts to write: openapi,postman,har,httpie")
+	flag.StringVar(&transport, "transport", "post", "GraphQL transport to document: post, get, or apq (Automatic Persisted Queries)")
 	flag.Parse()
 
-	if (schemaPath == "" || queryPath == "" || endpoint == "") && interactiveEnabled(interactive) {
+	if ((schemaPath == "" && schemaFromEndpoint == "") || queryPath == "" || endpoint == "") && interactiveEnabled(interactive) {
 		fmt.Println("Interactive mode: let's collect the missing inputs.")
 		if schemaPath == "" {
 			schemaPath = promptExistingFile("Path to GraphQL schema SDL (.graphql/.gql)")
@@ -63,17 +82,26 @@ func main() {
 			endpoint = promptString("GraphQL HTTP endpoint URL", "")
 		}
 	}
-	if schemaPath == "" || queryPath == "" || endpoint == "" {
-		fatalf("--schema, --query, and --endpoint are required")
+	if (schemaPath == "" && schemaFromEndpoint == "") || queryPath == "" || endpoint == "" {
+		fatalf("--schema (or --schema-from-endpoint), --query, and --endpoint are required")
 	}
 
-	schemaSDL, err := ioutil.ReadFile(schemaPath)
-	if err != nil {
-		fatalf("failed to read schema: %v", err)
-	}
-	gqlSchema, err := gqlparser.LoadSchema(&ast.Source{Name: path.Base(schemaPath), Input: string(schemaSDL)})
-	if err != nil {
-		fatalf("failed to parse schema: %v", err)
+	var gqlSchema *ast.Schema
+	var err error
+	if schemaFromEndpoint != "" {
+		gqlSchema, err = loadSchemaFromIntrospection(schemaFromEndpoint, headers.headers, insecureSkipVerify)
+		if err != nil {
+			fatalf("failed to load schema via introspection: %v", err)
+		}
+	} else {
+		schemaSDL, readErr := ioutil.ReadFile(schemaPath)
+		if readErr != nil {
+			fatalf("failed to read schema: %v", readErr)
+		}
+		gqlSchema, err = gqlparser.LoadSchema(&ast.Source{Name: path.Base(schemaPath), Input: withBuiltinDirectives(string(schemaSDL))})
+		if err != nil {
+			fatalf("failed to parse schema: %v", err)
+		}
 	}
 
 	queryDocBytes, err := ioutil.ReadFile(queryPath)
@@ -127,7 +155,21 @@ func main() {
 		exampleVars = buildVariablesExample(gqlSchema, op)
 	}
 
-	spec, err := buildOpenAPISpec(title, version, endpoint, string(queryDocBytes), varSchemaRef, required)
+	switch transport {
+	case "post", "get", "apq":
+	default:
+		fatalf("unknown --transport %q, expected post, get, or apq", transport)
+	}
+
+	var spec *openapi3.T
+	switch mode {
+	case "rest":
+		spec, err = buildRESTSpec(gqlSchema, op, title, version, endpoint, parsePathTemplates(pathTemplate))
+	case "graphql", "":
+		spec, err = buildOpenAPISpec(title, version, endpoint, string(queryDocBytes), varSchemaRef, required, gqlSchema, op, queryDoc.Fragments, transport)
+	default:
+		fatalf("unknown --mode %q, expected graphql or rest", mode)
+	}
 	if err != nil {
 		fatalf("failed to build OpenAPI spec: %v", err)
 	}
@@ -163,13 +205,17 @@ func main() {
 		}
 	}
 
-	// Write spec
-	if err := writeSpec(spec, outPath, format); err != nil {
+	// Write spec (and any other requested artifacts)
+	emitList, err := parseEmitList(emit)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if err := emitAll(emitList, spec, outPath, format, endpoint, string(queryDocBytes), exampleVars); err != nil {
 		fatalf("failed to write spec: %v", err)
 	}
 
 	// Print curl example to stdout
-	curl := buildCurl(endpoint, string(queryDocBytes), exampleVars)
+	curl := buildCurlTransport(transport, endpoint, string(queryDocBytes), exampleVars)
 	fmt.Println("\n# Example curl:\n" + curl)
 }
 
@@ -222,14 +268,23 @@ func buildVariablesSchema(schema *ast.Schema, op *ast.OperationDefinition) (*ope
 }
 
 func graphqlTypeToJSONSchema(schema *ast.Schema, t *ast.Type) *openapi3.SchemaRef {
+	return graphqlTypeToJSONSchemaVisited(schema, t, map[string]bool{})
+}
+
+// graphqlTypeToJSONSchemaVisited is graphqlTypeToJSONSchema's recursive
+// implementation. It threads a visited-type set down the ancestor chain so
+// that a self- or mutually-referential object/input type (legal in
+// GraphQL, and not something parser.ParseQuery validates away) renders as a
+// $ref-free placeholder instead of recursing forever.
+func graphqlTypeToJSONSchemaVisited(schema *ast.Schema, t *ast.Type, visited map[string]bool) *openapi3.SchemaRef {
 	if t.Elem != nil { // list
-		items := graphqlTypeToJSONSchema(schema, t.Elem)
- 	arr := openapi3.NewArraySchema()
-	arr.Items = items
-	if t.NonNull {
-		arr.Nullable = false
-	}
-	return &openapi3.SchemaRef{Value: arr}
+		items := graphqlTypeToJSONSchemaVisited(schema, t.Elem, visited)
+		arr := openapi3.NewArraySchema()
+		arr.Items = items
+		if t.NonNull {
+			arr.Nullable = false
+		}
+		return &openapi3.SchemaRef{Value: arr}
 	}
 	named := t.Name()
 	// non-null only affects required at parent level; we can keep type as is
@@ -258,11 +313,22 @@ func graphqlTypeToJSONSchema(schema *ast.Schema, t *ast.Type) *openapi3.SchemaRe
 				s.Enum = vals
 				return s.NewRef()
 			}
-			if def.Kind == ast.InputObject {
+			if def.Kind == ast.InputObject || def.Kind == ast.Object || def.Kind == ast.Interface {
+				if visited[named] {
+					s := openapi3.NewObjectSchema()
+					s.Description = fmt.Sprintf("circular reference to %s", named)
+					return &openapi3.SchemaRef{Value: s}
+				}
+				childVisited := make(map[string]bool, len(visited)+1)
+				for k := range visited {
+					childVisited[k] = true
+				}
+				childVisited[named] = true
+
 				props := map[string]*openapi3.SchemaRef{}
 				req := []string{}
 				for _, f := range def.Fields {
-					props[f.Name] = graphqlTypeToJSONSchema(schema, f.Type)
+					props[f.Name] = graphqlTypeToJSONSchemaVisited(schema, f.Type, childVisited)
 					if f.Type.NonNull {
 						req = append(req, f.Name)
 					}
@@ -289,14 +355,19 @@ func graphqlTypeToJSONSchema(schema *ast.Schema, t *ast.Type) *openapi3.SchemaRe
 func buildVariablesExample(schema *ast.Schema, op *ast.OperationDefinition) map[string]any {
 	out := map[string]any{}
 	for _, v := range op.VariableDefinitions {
-		out[v.Variable] = exampleForType(schema, v.Type)
+		out[v.Variable] = exampleForType(schema, v.Type, map[string]bool{})
 	}
 	return out
 }
 
-func exampleForType(schema *ast.Schema, t *ast.Type) any {
+// exampleForType is buildVariablesExample's recursive implementation. Like
+// graphqlTypeToJSONSchemaVisited, it threads a visited-type set down the
+// ancestor chain so a self- or mutually-referential input type used as an
+// operation variable renders as nil at the back-edge instead of recursing
+// forever.
+func exampleForType(schema *ast.Schema, t *ast.Type, visited map[string]bool) any {
 	if t.Elem != nil { // list
-		return []any{exampleForType(schema, t.Elem)}
+		return []any{exampleForType(schema, t.Elem, visited)}
 	}
 	switch t.Name() {
 	case "Int":
@@ -310,7 +381,8 @@ func exampleForType(schema *ast.Schema, t *ast.Type) any {
 	case "ID":
 		return "id"
 	default:
-		if def := schema.Types[t.Name()]; def != nil {
+		named := t.Name()
+		if def := schema.Types[named]; def != nil {
 			if def.Kind == ast.Enum {
 				if len(def.EnumValues) > 0 {
 					return def.EnumValues[0].Name
@@ -318,9 +390,18 @@ func exampleForType(schema *ast.Schema, t *ast.Type) any {
 				return "VALUE"
 			}
 			if def.Kind == ast.InputObject {
+				if visited[named] {
+					return nil
+				}
+				childVisited := make(map[string]bool, len(visited)+1)
+				for k := range visited {
+					childVisited[k] = true
+				}
+				childVisited[named] = true
+
 				obj := map[string]any{}
 				for _, f := range def.Fields {
-					obj[f.Name] = exampleForType(schema, f.Type)
+					obj[f.Name] = exampleForType(schema, f.Type, childVisited)
 				}
 				return obj
 			}
@@ -329,7 +410,7 @@ func exampleForType(schema *ast.Schema, t *ast.Type) any {
 	}
 }
 
-func buildOpenAPISpec(title, version, endpoint, query string, varsSchema *openapi3.SchemaRef, required []string) (*openapi3.T, error) {
+func buildOpenAPISpec(title, version, endpoint, query string, varsSchema *openapi3.SchemaRef, required []string, gqlSchema *ast.Schema, queryOp *ast.OperationDefinition, fragments ast.FragmentDefinitionList, transport string) (*openapi3.T, error) {
 	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
@@ -347,41 +428,112 @@ func buildOpenAPISpec(title, version, endpoint, query string, varsSchema *openap
 			Version: version,
 			Description: fmt.Sprintf("Auto-generated from GraphQL query.\n\nThis endpoint wraps the GraphQL operation as a REST-like POST."),
 		},
-		Servers: openapi3.Servers{{URL: serverURL}},
-		Paths:   openapi3.NewPaths(),
+		Servers:    openapi3.Servers{{URL: serverURL}},
+		Paths:      openapi3.NewPaths(),
+		Components: openapi3.Components{Schemas: openapi3.Schemas{}},
 	}
 
-	// Request body schema: { query: string, variables: <varsSchema> }
-	reqSchema := openapi3.NewObjectSchema()
-	reqSchema.Properties = map[string]*openapi3.SchemaRef{
-		"query":     openapi3.NewStringSchema().NewRef(),
-		"variables": varsSchema,
+	rootType, _ := rootTypeAndMethod(gqlSchema, queryOp)
+	builder := newResponseSchemaBuilder(spec, gqlSchema, fragments)
+	var respData *openapi3.SchemaRef
+	if rootType != nil {
+		respData = builder.dataSchema(rootType.Name, queryOp.SelectionSet)
+	} else {
+		respData = &openapi3.SchemaRef{Value: openapi3.NewObjectSchema()}
 	}
-	reqSchema.Required = []string{"query"}
-	content := openapi3.NewContentWithJSONSchema(reqSchema)
-
-	respData := openapi3.NewObjectSchema()
-	respErr := openapi3.NewArraySchema()
-	respErr.Items = &openapi3.SchemaRef{Value: openapi3.NewObjectSchema()}
 	respSchema := openapi3.NewObjectSchema()
 	respSchema.Properties = map[string]*openapi3.SchemaRef{
-		"data":   {Value: respData},
-		"errors": {Value: respErr},
-	}
-
-	op := &openapi3.Operation{
-		Summary:     fmt.Sprintf("Invoke GraphQL operation %s", opNameOrDefault(query)),
-		Description: "Send the GraphQL query and variables as JSON.",
-		RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: content}},
+		"data": respData,
+		"errors": {Value: func() *openapi3.Schema {
+			arr := openapi3.NewArraySchema()
+			arr.Items = graphqlErrorSchema()
+			return arr
+		}()},
 	}
 	responses := openapi3.NewResponses()
 	resp := &openapi3.Response{Description: ptr("OK")}
 	resp.Content = openapi3.NewContentWithJSONSchema(respSchema)
 	responses.Set("200", &openapi3.ResponseRef{Value: resp})
-	op.Responses = responses
 
 	pi := &openapi3.PathItem{}
-	pi.Post = op
+	summary := fmt.Sprintf("Invoke GraphQL operation %s", opNameOrDefault(query))
+
+	if directivePath, directiveMethod, ok := operationRestOverride(queryOp); ok {
+		if directivePath == "" {
+			directivePath = p
+		}
+		if directiveMethod == "" {
+			directiveMethod = "POST"
+		}
+		directiveOp := buildDirectiveRESTOperation(gqlSchema, queryOp, summary, directivePath, responses)
+		switch directiveMethod {
+		case "GET":
+			pi.Get = directiveOp
+		default:
+			pi.Post = directiveOp
+		}
+		spec.Paths.Set(directivePath, pi)
+		return spec, nil
+	}
+
+	switch transport {
+	case "get":
+		op := &openapi3.Operation{
+			Summary:     summary,
+			Description: "Send the GraphQL query as URL-encoded query parameters (GraphQL-over-GET).",
+			Parameters: openapi3.Parameters{
+				{Value: &openapi3.Parameter{Name: "query", In: "query", Required: true, Schema: openapi3.NewStringSchema().NewRef()}},
+				{Value: &openapi3.Parameter{Name: "variables", In: "query", Schema: openapi3.NewStringSchema().NewRef(), Description: "JSON-encoded variables object"}},
+				{Value: &openapi3.Parameter{Name: "operationName", In: "query", Schema: openapi3.NewStringSchema().NewRef()}},
+			},
+			Responses: responses,
+		}
+		pi.Get = op
+	case "apq":
+		extSchema := persistedQueryExtensionsSchema()
+
+		getOp := &openapi3.Operation{
+			Summary:     summary + " (persisted query retry)",
+			Description: "Automatic Persisted Queries: retry with only the query hash once it has been registered.",
+			Parameters: openapi3.Parameters{
+				{Value: &openapi3.Parameter{Name: "variables", In: "query", Schema: openapi3.NewStringSchema().NewRef(), Description: "JSON-encoded variables object"}},
+				{Value: &openapi3.Parameter{Name: "operationName", In: "query", Schema: openapi3.NewStringSchema().NewRef()}},
+				{Value: &openapi3.Parameter{Name: "extensions", In: "query", Required: true, Schema: openapi3.NewStringSchema().NewRef(), Description: "JSON-encoded { persistedQuery: { version, sha256Hash } }"}},
+			},
+			Responses: responses,
+		}
+		pi.Get = getOp
+
+		reqSchema := openapi3.NewObjectSchema()
+		reqSchema.Properties = map[string]*openapi3.SchemaRef{
+			"query":      openapi3.NewStringSchema().NewRef(),
+			"variables":  varsSchema,
+			"extensions": extSchema,
+		}
+		reqSchema.Required = []string{"query"}
+		postOp := &openapi3.Operation{
+			Summary:     summary + " (persisted query first-send fallback)",
+			Description: "First send: register the persisted query hash by sending the full query once.",
+			RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(reqSchema)}},
+			Responses:   responses,
+		}
+		pi.Post = postOp
+	default:
+		reqSchema := openapi3.NewObjectSchema()
+		reqSchema.Properties = map[string]*openapi3.SchemaRef{
+			"query":     openapi3.NewStringSchema().NewRef(),
+			"variables": varsSchema,
+		}
+		reqSchema.Required = []string{"query"}
+		op := &openapi3.Operation{
+			Summary:     summary,
+			Description: "Send the GraphQL query and variables as JSON.",
+			RequestBody: &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{Required: true, Content: openapi3.NewContentWithJSONSchema(reqSchema)}},
+			Responses:   responses,
+		}
+		pi.Post = op
+	}
+
 	spec.Paths.Set(p, pi)
 	return spec, nil
 }
@@ -412,7 +564,7 @@ func writeSpec(spec *openapi3.T, outPath, format string) error {
 	var err error
 	switch strings.ToLower(format) {
 	case "yaml", "yml":
-		b, err = yaml.Marshal(spec)
+		b, err = specToYAML(spec)
 	case "json":
 		b, err = spec.MarshalJSON()
 	default:
@@ -426,16 +578,34 @@ func writeSpec(spec *openapi3.T, outPath, format string) error {
 		fmt.Println()
 		return nil
 	}
+	return writeFile(outPath, b)
+}
+
+func specToYAML(spec *openapi3.T) ([]byte, error) {
+	return yaml.Marshal(spec)
+}
+
+func writeFile(outPath string, b []byte) error {
 	return ioutil.WriteFile(outPath, b, 0o644)
 }
 
-func buildCurl(endpoint, query string, variables map[string]any) string {
-	body := map[string]any{
-		"query":     query,
-		"variables": variables,
+// buildCurlTransport renders the curl example for the selected --transport:
+// post (default, JSON body), get (GraphQL-over-GET), or apq (persisted
+// query GET with a POST fallback).
+func buildCurlTransport(transport, endpoint, query string, variables map[string]any) string {
+	switch transport {
+	case "get":
+		return buildCurlGET(endpoint, query, variables)
+	case "apq":
+		return buildCurlAPQ(endpoint, query, variables)
+	default:
+		body := map[string]any{
+			"query":     query,
+			"variables": variables,
+		}
+		b, _ := json.Marshal(body)
+		return fmt.Sprintf("curl -X POST %s -H 'Content-Type: application/json' -d '%s'", shellEscape(endpoint), shellEscape(string(b)))
 	}
-	b, _ := json.Marshal(body)
-	return fmt.Sprintf("curl -X POST %s -H 'Content-Type: application/json' -d '%s'", shellEscape(endpoint), shellEscape(string(b)))
 }
 
 func shellEscape(s string) string {
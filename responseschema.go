@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// responseSchemaBuilder walks a GraphQL selection set against the schema and
+// builds the matching OpenAPI response schema, deduping identical
+// type+sub-selection combinations into components.schemas.
+type responseSchemaBuilder struct {
+	schema    *ast.Schema
+	fragments ast.FragmentDefinitionList
+	spec      *openapi3.T
+	byName    map[string]*openapi3.SchemaRef // component name -> $ref, for dedup
+}
+
+func newResponseSchemaBuilder(spec *openapi3.T, schema *ast.Schema, fragments ast.FragmentDefinitionList) *responseSchemaBuilder {
+	if spec.Components.Schemas == nil {
+		spec.Components.Schemas = openapi3.Schemas{}
+	}
+	return &responseSchemaBuilder{
+		schema:    schema,
+		fragments: fragments,
+		spec:      spec,
+		byName:    map[string]*openapi3.SchemaRef{},
+	}
+}
+
+// dataSchema builds the top-level "data" schema for an operation's root
+// selection set (Query/Mutation/Subscription).
+func (b *responseSchemaBuilder) dataSchema(rootTypeName string, sel ast.SelectionSet) *openapi3.SchemaRef {
+	return b.selectionSetSchema(rootTypeName, sel)
+}
+
+func (b *responseSchemaBuilder) selectionSetSchema(typeName string, sel ast.SelectionSet) *openapi3.SchemaRef {
+	name := b.componentName(typeName, sel)
+	if existing, ok := b.byName[name]; ok {
+		return existing
+	}
+	// Reserve the ref before recursing so self-referential selections don't loop forever.
+	placeholder := &openapi3.SchemaRef{Ref: "#/components/schemas/" + name}
+	b.byName[name] = placeholder
+
+	direct, variants := b.partitionSelection(typeName, sel)
+	base := openapi3.NewObjectSchema()
+	base.Properties = b.fieldsSchema(typeName, direct)
+
+	var schemaValue *openapi3.Schema
+	if len(variants) == 0 {
+		schemaValue = base
+	} else {
+		// Abstract type (interface/union) with type-narrowing inline
+		// fragments/fragment spreads: the response is the common fields plus
+		// exactly one of the concrete-type branches, modeled as allOf[base, oneOf[...]].
+		concreteTypes := make([]string, 0, len(variants))
+		for t := range variants {
+			concreteTypes = append(concreteTypes, t)
+		}
+		sort.Strings(concreteTypes)
+
+		variantSchemas := make(openapi3.SchemaRefs, 0, len(concreteTypes))
+		for _, concreteType := range concreteTypes {
+			vObj := openapi3.NewObjectSchema()
+			vObj.Properties = b.fieldsSchema(concreteType, variants[concreteType])
+			variantSchemas = append(variantSchemas, &openapi3.SchemaRef{Value: vObj})
+		}
+		schemaValue = &openapi3.Schema{
+			AllOf: openapi3.SchemaRefs{
+				{Value: base},
+				{Value: &openapi3.Schema{OneOf: variantSchemas}},
+			},
+		}
+	}
+
+	b.spec.Components.Schemas[name] = &openapi3.SchemaRef{Value: schemaValue}
+	return placeholder
+}
+
+// fieldsSchema resolves a flat list of fields against resolveType (the
+// concrete GraphQL type whose FieldDefinitions should be used) and builds
+// their OpenAPI properties.
+func (b *responseSchemaBuilder) fieldsSchema(resolveType string, fields []*ast.Field) map[string]*openapi3.SchemaRef {
+	def := b.schema.Types[resolveType]
+	props := map[string]*openapi3.SchemaRef{}
+	seen := map[string]bool{}
+	for _, f := range fields {
+		if seen[f.Alias] {
+			continue
+		}
+		var fieldDef *ast.FieldDefinition
+		if def != nil {
+			fieldDef = def.Fields.ForName(f.Name)
+		}
+		if fieldDef == nil {
+			continue
+		}
+		seen[f.Alias] = true
+		props[f.Alias] = b.fieldSchema(fieldDef, f)
+	}
+	return props
+}
+
+func (b *responseSchemaBuilder) fieldSchema(fieldDef *ast.FieldDefinition, f *ast.Field) *openapi3.SchemaRef {
+	if fieldDef.Type.Elem != nil {
+		items := b.fieldTypeSchema(fieldDef.Type.Elem, f)
+		arr := openapi3.NewArraySchema()
+		arr.Items = items
+		return &openapi3.SchemaRef{Value: arr}
+	}
+	return b.fieldTypeSchema(fieldDef.Type, f)
+}
+
+func (b *responseSchemaBuilder) fieldTypeSchema(t *ast.Type, f *ast.Field) *openapi3.SchemaRef {
+	named := t.Name()
+	def := b.schema.Types[named]
+	if def != nil && len(f.SelectionSet) > 0 &&
+		(def.Kind == ast.Object || def.Kind == ast.Interface || def.Kind == ast.Union) {
+		return b.selectionSetSchema(named, f.SelectionSet)
+	}
+	return graphqlTypeToJSONSchema(b.schema, t)
+}
+
+// partitionSelection splits a selection set into the fields selected
+// directly against parentType (including fragments that don't narrow the
+// type, merged in the way a GraphQL executor would) and, separately, the
+// fields selected per concrete type via a type-narrowing inline fragment or
+// fragment spread (e.g. `... on Droid { ... }` on an interface or union).
+// Each variant's fields are resolved against its own type condition rather
+// than parentType, so interface/union fields aren't silently dropped.
+func (b *responseSchemaBuilder) partitionSelection(parentType string, sel ast.SelectionSet) ([]*ast.Field, map[string][]*ast.Field) {
+	var direct []*ast.Field
+	variants := map[string][]*ast.Field{}
+
+	var walk func(ast.SelectionSet)
+	walk = func(sel ast.SelectionSet) {
+		for _, s := range sel {
+			switch v := s.(type) {
+			case *ast.Field:
+				direct = append(direct, v)
+			case *ast.FragmentSpread:
+				frag := b.fragments.ForName(v.Name)
+				if frag == nil {
+					continue
+				}
+				if frag.TypeCondition == "" || frag.TypeCondition == parentType {
+					walk(frag.SelectionSet)
+				} else {
+					variants[frag.TypeCondition] = append(variants[frag.TypeCondition], collectDirectFields(b.fragments, frag.SelectionSet)...)
+				}
+			case *ast.InlineFragment:
+				if v.TypeCondition == "" || v.TypeCondition == parentType {
+					walk(v.SelectionSet)
+				} else {
+					variants[v.TypeCondition] = append(variants[v.TypeCondition], collectDirectFields(b.fragments, v.SelectionSet)...)
+				}
+			}
+		}
+	}
+	walk(sel)
+	return direct, variants
+}
+
+// collectDirectFields flattens a selection set's own fields, expanding every
+// fragment spread/inline fragment inside it into the same flat list whatever
+// its type condition (no further narrowing into variants). Used once a
+// variant's type condition has already been established by partitionSelection.
+func collectDirectFields(fragments ast.FragmentDefinitionList, sel ast.SelectionSet) []*ast.Field {
+	var out []*ast.Field
+	for _, s := range sel {
+		switch v := s.(type) {
+		case *ast.Field:
+			out = append(out, v)
+		case *ast.FragmentSpread:
+			if frag := fragments.ForName(v.Name); frag != nil {
+				out = append(out, collectDirectFields(fragments, frag.SelectionSet)...)
+			}
+		case *ast.InlineFragment:
+			out = append(out, collectDirectFields(fragments, v.SelectionSet)...)
+		}
+	}
+	return out
+}
+
+// componentName derives a stable name for a type+selection combination so
+// identical sub-selections dedupe into a single components.schemas entry.
+func (b *responseSchemaBuilder) componentName(typeName string, sel ast.SelectionSet) string {
+	h := fnv.New32a()
+	h.Write([]byte(canonicalSelection(sel)))
+	return fmt.Sprintf("%s_%x", typeName, h.Sum32())
+}
+
+// canonicalSelection renders a selection set as a deterministic string
+// (alias, field name, sorted sub-selection) suitable for hashing.
+func canonicalSelection(sel ast.SelectionSet) string {
+	var names []string
+	for _, s := range sel {
+		switch v := s.(type) {
+		case *ast.Field:
+			names = append(names, fmt.Sprintf("%s:%s(%s)", v.Alias, v.Name, canonicalSelection(v.SelectionSet)))
+		case *ast.FragmentSpread:
+			names = append(names, "..."+v.Name)
+		case *ast.InlineFragment:
+			names = append(names, fmt.Sprintf("...on %s(%s)", v.TypeCondition, canonicalSelection(v.SelectionSet)))
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// graphqlErrorSchema describes the "errors" array per the GraphQL-over-HTTP
+// spec: message, path, locations, extensions.
+func graphqlErrorSchema() *openapi3.SchemaRef {
+	loc := openapi3.NewObjectSchema()
+	loc.Properties = map[string]*openapi3.SchemaRef{
+		"line":   openapi3.NewIntegerSchema().NewRef(),
+		"column": openapi3.NewIntegerSchema().NewRef(),
+	}
+
+	path := openapi3.NewArraySchema()
+	path.Items = &openapi3.SchemaRef{Value: openapi3.NewStringSchema()}
+
+	locations := openapi3.NewArraySchema()
+	locations.Items = &openapi3.SchemaRef{Value: loc}
+
+	extensions := openapi3.NewObjectSchema()
+	extensions.AdditionalProperties = openapi3.AdditionalProperties{Has: ptr(true)}
+
+	errItem := openapi3.NewObjectSchema()
+	errItem.Properties = map[string]*openapi3.SchemaRef{
+		"message":    openapi3.NewStringSchema().NewRef(),
+		"path":       {Value: path},
+		"locations":  {Value: locations},
+		"extensions": {Value: extensions},
+	}
+	errItem.Required = []string{"message"}
+	return &openapi3.SchemaRef{Value: errItem}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// parsePathTemplates turns a comma-separated "field=/path/{arg}" list (as
+// passed via --path-template) into a lookup from field name to template.
+func parsePathTemplates(raw string) map[string]string {
+	out := map[string]string{}
+	if raw == "" {
+		return out
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		out[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return out
+}
+
+// buildRESTSpec produces an OpenAPI spec with one path per top-level field in
+// the operation's selection set, instead of the single /graphql passthrough
+// that buildOpenAPISpec emits. Queries become GET /{field}, mutations become
+// POST /{field}. Scalar/enum arguments become query (or path) parameters;
+// input object arguments become the JSON request body.
+func buildRESTSpec(schema *ast.Schema, op *ast.OperationDefinition, title, version, endpoint string, pathTemplates map[string]string) (*openapi3.T, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	serverURL := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+
+	spec := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       title,
+			Version:     version,
+			Description: "Auto-generated from a GraphQL operation, one REST path per top-level field.",
+		},
+		Servers: openapi3.Servers{{URL: serverURL}},
+		Paths:   openapi3.NewPaths(),
+	}
+
+	rootType, method := rootTypeAndMethod(schema, op)
+	if rootType == nil {
+		return nil, fmt.Errorf("schema has no root type for operation %s", op.Operation)
+	}
+
+	for _, sel := range op.SelectionSet {
+		field, ok := sel.(*ast.Field)
+		if !ok {
+			continue
+		}
+		fieldDef := rootType.Fields.ForName(field.Name)
+		if fieldDef == nil {
+			return nil, fmt.Errorf("field %q not found on %s", field.Name, rootType.Name)
+		}
+
+		template, explicit := pathTemplates[field.Alias]
+		if !explicit {
+			template, explicit = pathTemplates[field.Name]
+		}
+		if !explicit {
+			template = "/" + field.Name
+		}
+		pathParams := pathParamNames(template)
+
+		restOp := &openapi3.Operation{
+			Summary:     fmt.Sprintf("Invoke GraphQL field %s", field.Name),
+			Description: fmt.Sprintf("REST mapping generated for the %s field.", field.Name),
+		}
+
+		var bodyProps map[string]*openapi3.SchemaRef
+		var bodyRequired []string
+
+		for _, arg := range field.Arguments {
+			argDef := fieldDef.Arguments.ForName(arg.Name)
+			if argDef == nil {
+				continue
+			}
+			schemaRef := graphqlTypeToJSONSchema(schema, argDef.Type)
+			namedType := argDef.Type.Name()
+			def := schema.Types[namedType]
+			isInputObject := def != nil && def.Kind == ast.InputObject
+
+			if !isInputObject {
+				in := "query"
+				if pathParams[arg.Name] {
+					in = "path"
+				}
+				param := &openapi3.Parameter{
+					Name:     arg.Name,
+					In:       in,
+					Required: in == "path" || argDef.Type.NonNull,
+					Schema:   schemaRef,
+				}
+				restOp.Parameters = append(restOp.Parameters, &openapi3.ParameterRef{Value: param})
+				continue
+			}
+
+			if bodyProps == nil {
+				bodyProps = map[string]*openapi3.SchemaRef{}
+			}
+			bodyProps[arg.Name] = schemaRef
+			if argDef.Type.NonNull {
+				bodyRequired = append(bodyRequired, arg.Name)
+			}
+		}
+
+		if len(bodyProps) > 0 {
+			bodySchema := openapi3.NewObjectSchema()
+			bodySchema.Properties = bodyProps
+			if len(bodyRequired) > 0 {
+				bodySchema.Required = bodyRequired
+			}
+			restOp.RequestBody = &openapi3.RequestBodyRef{Value: &openapi3.RequestBody{
+				Required: true,
+				Content:  openapi3.NewContentWithJSONSchema(bodySchema),
+			}}
+		}
+
+		respSchema := fieldResponseSchema(schema, field, fieldDef)
+		responses := openapi3.NewResponses()
+		resp := &openapi3.Response{Description: ptr("OK")}
+		resp.Content = openapi3.NewContentWithJSONSchema(respSchema)
+		responses.Set("200", &openapi3.ResponseRef{Value: resp})
+		restOp.Responses = responses
+
+		pi := spec.Paths.Find(template)
+		if pi == nil {
+			pi = &openapi3.PathItem{}
+			spec.Paths.Set(template, pi)
+		}
+		switch method {
+		case "GET":
+			pi.Get = restOp
+		default:
+			pi.Post = restOp
+		}
+	}
+
+	return spec, nil
+}
+
+func rootTypeAndMethod(schema *ast.Schema, op *ast.OperationDefinition) (*ast.Definition, string) {
+	switch op.Operation {
+	case ast.Query:
+		return schema.Query, "GET"
+	case ast.Mutation:
+		return schema.Mutation, "POST"
+	case ast.Subscription:
+		return schema.Subscription, "POST"
+	default:
+		return nil, "POST"
+	}
+}
+
+// pathParamNames extracts the {name} placeholders from a path template.
+func pathParamNames(template string) map[string]bool {
+	out := map[string]bool{}
+	for _, seg := range strings.Split(template, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			out[seg[1:len(seg)-1]] = true
+		}
+	}
+	return out
+}
+
+// fieldResponseSchema builds the top-level {data, errors} envelope for a
+// single REST path, where "data" mirrors the plain fields of the field's own
+// selection set against the schema (see fieldDataSchema). The errors shape matches graphql-mode's
+// graphqlErrorSchema so both modes document the same error item.
+func fieldResponseSchema(schema *ast.Schema, field *ast.Field, fieldDef *ast.FieldDefinition) *openapi3.Schema {
+	envelope := openapi3.NewObjectSchema()
+	errs := openapi3.NewArraySchema()
+	errs.Items = graphqlErrorSchema()
+	envelope.Properties = map[string]*openapi3.SchemaRef{
+		"data":   fieldDataSchema(schema, field, fieldDef),
+		"errors": {Value: errs},
+	}
+	return envelope
+}
+
+// fieldDataSchema builds the plain (envelope-free) schema for a field's own
+// value, recursing into sub-fields without wrapping each level in another
+// data/errors envelope the way fieldResponseSchema does at the top level.
+// Only plain fields are followed; fragment spreads and inline fragments in
+// the selection set are skipped.
+func fieldDataSchema(schema *ast.Schema, field *ast.Field, fieldDef *ast.FieldDefinition) *openapi3.SchemaRef {
+	if len(field.SelectionSet) == 0 {
+		return graphqlTypeToJSONSchema(schema, fieldDef.Type)
+	}
+
+	named := fieldDef.Type.Name()
+	def := schema.Types[named]
+	inner := openapi3.NewObjectSchema()
+	if def != nil {
+		for _, sub := range field.SelectionSet {
+			subField, ok := sub.(*ast.Field)
+			if !ok {
+				continue
+			}
+			subDef := def.Fields.ForName(subField.Name)
+			if subDef == nil {
+				continue
+			}
+			if inner.Properties == nil {
+				inner.Properties = map[string]*openapi3.SchemaRef{}
+			}
+			inner.Properties[subField.Alias] = fieldDataSchema(schema, subField, subDef)
+		}
+	}
+
+	if fieldDef.Type.Elem != nil {
+		arr := openapi3.NewArraySchema()
+		arr.Items = &openapi3.SchemaRef{Value: inner}
+		return &openapi3.SchemaRef{Value: arr}
+	}
+	return &openapi3.SchemaRef{Value: inner}
+}
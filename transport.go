@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// apqHash returns the SHA-256 hex digest GraphQL's Automatic Persisted
+// Queries protocol uses to identify a query document.
+func apqHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// persistedQueryExtensions builds the `extensions.persistedQuery` envelope
+// APQ expects on both the GET retry and the POST fallback.
+func persistedQueryExtensions(query string) map[string]any {
+	return map[string]any{
+		"persistedQuery": map[string]any{
+			"version":    1,
+			"sha256Hash": apqHash(query),
+		},
+	}
+}
+
+// buildCurlGET renders a GraphQL-over-GET request per the GraphQL-over-HTTP
+// spec: query and variables as URL-encoded query parameters. No
+// operationName parameter is sent.
+func buildCurlGET(endpoint, query string, variables map[string]any) string {
+	return "curl " + shellEscape(graphQLGetURL(endpoint, query, variables, nil))
+}
+
+// buildCurlAPQ renders the two-step APQ flow: a GET using only the query
+// hash, with a POST fallback (including the full query) for when the
+// persisted query hasn't been registered with the server yet.
+func buildCurlAPQ(endpoint, query string, variables map[string]any) string {
+	getCurl := "curl " + shellEscape(graphQLGetURL(endpoint, "", variables, persistedQueryExtensions(query)))
+
+	body := map[string]any{
+		"query":      query,
+		"variables":  variables,
+		"extensions": persistedQueryExtensions(query),
+	}
+	b, _ := json.Marshal(body)
+	postCurl := fmt.Sprintf("curl -X POST %s -H 'Content-Type: application/json' -d '%s'", shellEscape(endpoint), shellEscape(string(b)))
+
+	return "# First attempt (GET, relies on the query already being persisted):\n" + getCurl +
+		"\n\n# Fallback if the server hasn't seen this query hash yet (registers it):\n" + postCurl
+}
+
+// persistedQueryExtensionsSchema describes the `extensions.persistedQuery`
+// envelope in OpenAPI terms, for the APQ request body.
+func persistedQueryExtensionsSchema() *openapi3.SchemaRef {
+	pq := openapi3.NewObjectSchema()
+	pq.Properties = map[string]*openapi3.SchemaRef{
+		"version":    openapi3.NewIntegerSchema().NewRef(),
+		"sha256Hash": openapi3.NewStringSchema().NewRef(),
+	}
+	pq.Required = []string{"version", "sha256Hash"}
+
+	ext := openapi3.NewObjectSchema()
+	ext.Properties = map[string]*openapi3.SchemaRef{
+		"persistedQuery": {Value: pq},
+	}
+	return &openapi3.SchemaRef{Value: ext}
+}
+
+func graphQLGetURL(endpoint, query string, variables map[string]any, extensions map[string]any) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	q := u.Query()
+	if query != "" {
+		q.Set("query", query)
+	}
+	if len(variables) > 0 {
+		if b, err := json.Marshal(variables); err == nil {
+			q.Set("variables", string(b))
+		}
+	}
+	if len(extensions) > 0 {
+		if b, err := json.Marshal(extensions); err == nil {
+			q.Set("extensions", string(b))
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// capturedExchange is the on-disk shape of --request: a single captured
+// GraphQL-as-REST HTTP request, optionally paired with the response that was
+// observed for it, so both sides can be checked against the generated spec
+// in one pass.
+type capturedExchange struct {
+	Method   string            `json:"method"`
+	Path     string            `json:"path"`
+	Query    string            `json:"query,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Body     json.RawMessage   `json:"body,omitempty"`
+	Response *capturedResponse `json:"response,omitempty"`
+}
+
+type capturedResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+type validationIssue struct {
+	location string
+	message  string
+}
+
+// cmdValidate implements `all-the-curls validate`: load a generated OpenAPI
+// spec and check a captured request/response pair against it, reporting
+// every violation instead of stopping at the first one.
+func cmdValidate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	specPath := fs.String("spec", "", "Path to the OpenAPI spec to validate against (yaml or json)")
+	requestPath := fs.String("request", "", "Path to a captured request/response JSON file")
+	fs.Parse(args)
+
+	if *specPath == "" || *requestPath == "" {
+		fatalf("validate: --spec and --request are required")
+	}
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromFile(*specPath)
+	if err != nil {
+		fatalf("validate: failed to load spec: %v", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		fatalf("validate: spec failed its own validation: %v", err)
+	}
+
+	router, err := legacyrouter.NewRouter(doc)
+	if err != nil {
+		fatalf("validate: failed to build router: %v", err)
+	}
+
+	raw, err := ioutil.ReadFile(*requestPath)
+	if err != nil {
+		fatalf("validate: failed to read --request: %v", err)
+	}
+	var exchange capturedExchange
+	if err := json.Unmarshal(raw, &exchange); err != nil {
+		fatalf("validate: --request is not valid JSON: %v", err)
+	}
+
+	issues, err := validateExchange(router, exchange)
+	if err != nil {
+		fatalf("validate: %v", err)
+	}
+	if len(issues) == 0 {
+		fmt.Println("OK: request/response conforms to the spec")
+		return
+	}
+	fmt.Fprintf(os.Stderr, "found %d issue(s):\n", len(issues))
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "  - [%s] %s\n", issue.location, issue.message)
+	}
+	os.Exit(1)
+}
+
+// validateExchange aggregates every validation failure it finds (parameters,
+// request body, response) rather than returning on the first one, unlike
+// openapi3filter.ValidateRequest's fail-fast behavior.
+func validateExchange(router routers.Router, exchange capturedExchange) ([]validationIssue, error) {
+	ctx := context.Background()
+	var issues []validationIssue
+
+	target := exchange.Path
+	if exchange.Query != "" {
+		target += "?" + strings.TrimPrefix(exchange.Query, "?")
+	}
+	var bodyReader *strings.Reader
+	if len(exchange.Body) > 0 {
+		bodyReader = strings.NewReader(string(exchange.Body))
+	} else {
+		bodyReader = strings.NewReader("")
+	}
+	httpReq := httptest.NewRequest(strings.ToUpper(exchange.Method), target, bodyReader)
+	for k, v := range exchange.Headers {
+		httpReq.Header.Set(k, v)
+	}
+	if httpReq.Header.Get("Content-Type") == "" && len(exchange.Body) > 0 {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	route, pathParams, err := router.FindRoute(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("no matching route for %s %s: %w", exchange.Method, exchange.Path, err)
+	}
+
+	reqInput := &openapi3filter.RequestValidationInput{
+		Request:    httpReq,
+		PathParams: pathParams,
+		Route:      route,
+	}
+
+	for _, paramRef := range route.Operation.Parameters {
+		if paramRef.Value == nil {
+			continue
+		}
+		if err := openapi3filter.ValidateParameter(ctx, reqInput, paramRef.Value); err != nil {
+			issues = append(issues, validationIssue{
+				location: fmt.Sprintf("request parameter %q", paramRef.Value.Name),
+				message:  err.Error(),
+			})
+		}
+	}
+	if route.Operation.RequestBody != nil {
+		if err := openapi3filter.ValidateRequestBody(ctx, reqInput, route.Operation.RequestBody.Value); err != nil {
+			issues = append(issues, validationIssue{location: "request body", message: err.Error()})
+		}
+	}
+
+	if exchange.Response != nil {
+		respHeader := http.Header{}
+		for k, v := range exchange.Response.Headers {
+			respHeader.Set(k, v)
+		}
+		respInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: reqInput,
+			Status:                 exchange.Response.Status,
+			Header:                 respHeader,
+		}
+		respInput.SetBodyBytes(exchange.Response.Body)
+		if err := openapi3filter.ValidateResponse(ctx, respInput); err != nil {
+			issues = append(issues, validationIssue{
+				location: fmt.Sprintf("response (status %d)", exchange.Response.Status),
+				message:  err.Error(),
+			})
+		}
+	}
+
+	return issues, nil
+}